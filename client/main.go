@@ -1,84 +1,81 @@
 package main
 
 import (
-	"io"
-	"live_chat/src/constant"
+	"context"
+	"live_chat/src/config"
+	"live_chat/src/event"
+	"live_chat/src/player"
+	"live_chat/src/queue"
+	"live_chat/src/setup"
 	"live_chat/src/websocket"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
-	"runtime"
+	"syscall"
 )
 
 func main() {
-	var wsURL string
-	if len(os.Args) < 2 {
-		wsURL = "ws://" + constant.IP_ADDR_SERVER + "/ws"
-	} else {
-		wsURL = os.Args[1]
+	explicitURL := ""
+	if len(os.Args) >= 2 {
+		explicitURL = os.Args[1]
 	}
 
-	if runtime.GOOS == "windows" {
-		setupStartup()
+	if err := config.Load(); err != nil {
+		log.Println("config.Load:", err)
 	}
+	cfg := config.Current()
 
-	for {
-		websocket.ConnectToWebsocket(wsURL)
+	if cfg.Autostart {
+		if destDir, err := setup.DefaultInstallDir(); err != nil {
+			log.Println("setup.DefaultInstallDir:", err)
+		} else if _, err := setup.Install(destDir); err != nil {
+			log.Println("setup.Install:", err)
+		}
 	}
-}
 
-func setupStartup() {
-	exPath, err := os.Executable()
-	if err != nil {
-		log.Println("Erreur lors de la récupération du chemin de l'exécutable:", err)
-		return
+	// Autostart, FirewallPort et QueueLimits ne sont lus qu'ici, au démarrage: changer
+	// ces clés dans config.json n'a d'effet qu'après un redémarrage du
+	// client, contrairement au reste de la config (Server, Events, ...) qui
+	// est relu à chaud via config.Current().
+	if err := setup.NewFirewall().AllowPort(cfg.FirewallPort); err != nil {
+		log.Println("setup.Firewall.AllowPort:", err)
 	}
 
-	programFilesPath := os.Getenv("ProgramFiles")
-	if programFilesPath == "" {
-		log.Println("Variable d'environnement non trouvée.")
-		return
+	if exPath, err := os.Executable(); err == nil {
+		if err := player.Start(filepath.Dir(exPath)); err != nil {
+			log.Println("mpv indisponible, repli sur oto:", err)
+		}
 	}
 
-	destDir := filepath.Join(programFilesPath, "live_chat")
-	destPath := filepath.Join(destDir, filepath.Base(exPath))
-
-	if _, err := os.Stat(destPath); !os.IsNotExist(err) {
-		log.Println("good")
-	} else {
-		if err := os.MkdirAll(destDir, 0755); err != nil {
-			log.Println("Impossible de créer le répertoire de destination:", err)
-			return
-		}
-		srcFile, err := os.Open(exPath)
-		if err != nil {
-			log.Println("Impossible d'ouvrir le fichier source:", err)
-			return
-		}
-		defer srcFile.Close()
+	queueCfg := queue.Config{
+		QueueLimit:        cfg.QueueLimits.QueueLimit,
+		RequestsPerMinute: cfg.QueueLimits.RequestsPerMinute,
+		VoteSkipRatio:     cfg.QueueLimits.VoteSkipRatio,
+		VoteSkipWindow:    cfg.VoteSkipWindow(),
+	}
+	queueManager := queue.Init(queueCfg, event.PlayQueueItem, event.StopPlayback)
+	go queue.Serve(":"+cfg.FirewallPort, queueManager)
 
-		destFile, err := os.Create(destPath)
-		if err != nil {
-			log.Println("Impossible de créer le fichier de destination:", err)
-			return
+	client := websocket.NewClient(func() string {
+		if explicitURL != "" {
+			return explicitURL
 		}
-		defer destFile.Close()
+		return "ws://" + config.Current().Server + "/ws"
+	})
+	websocket.SetDefault(client)
+	event.SetNotifier(client.SendJSON)
 
-		_, err = io.Copy(destFile, srcFile)
-		if err != nil {
-			log.Println("Erreur lors de la copie du fichier:", err)
-			return
-		}
-	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	startupPath := filepath.Join(os.Getenv("APPDATA"), "Microsoft", "Windows", "Start Menu", "Programs", "Startup")
-	vbsContent := `Set WshShell = WScript.CreateObject("WScript.Shell")` + "\n" +
-		`WshShell.Run Chr(34) & "` + destPath + `" & Chr(34), 0` + "\n" +
-		`Set WshShell = Nothing`
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		log.Println("Arrêt demandé, fermeture de la connexion WebSocket...")
+		cancel()
+	}()
 
-	vbsPath := filepath.Join(startupPath, "start_live_chat.vbs")
-	err = os.WriteFile(vbsPath, []byte(vbsContent), 0644)
-	if err != nil {
-		log.Println("Impossible de créer le script de démarrage:", err)
-	}
+	client.Run(ctx)
 }
@@ -0,0 +1,100 @@
+// Package config charge la configuration du client depuis un fichier JSON
+// rechargeable à chaud, pour que les déploiements existants puissent changer
+// de serveur sans recompiler ni redémarrer.
+package config
+
+import (
+	"live_chat/src/constant"
+	"sync/atomic"
+	"time"
+)
+
+// ReconnectBackoff règle le backoff exponentiel de websocket.Client.
+type ReconnectBackoff struct {
+	BaseMillis int `json:"base_ms"`
+	CapSeconds int `json:"cap_s"`
+}
+
+// TTS règle le fournisseur de synthèse vocale par défaut.
+type TTS struct {
+	DefaultLang string `json:"default_lang"`
+}
+
+// QueueLimits reflète queue.Config, en JSON pour être configurable sans
+// recompiler. Lu une seule fois au démarrage (main.go): un changement
+// n'a d'effet qu'après un redémarrage du client.
+type QueueLimits struct {
+	QueueLimit            int     `json:"queue_limit"`
+	RequestsPerMinute     int     `json:"requests_per_minute"`
+	VoteSkipRatio         float64 `json:"vote_skip_ratio"`
+	VoteSkipWindowSeconds int     `json:"vote_skip_window_s"`
+}
+
+// Config est l'ensemble des réglages du client. QueueLimits, FirewallPort et
+// Autostart ne sont lus qu'au démarrage; les autres sont relus à chaud via
+// Current().
+type Config struct {
+	Server           string           `json:"server"`
+	ReconnectBackoff ReconnectBackoff `json:"reconnect_backoff"`
+	TTS              TTS              `json:"tts"`
+	QueueLimits      QueueLimits      `json:"queue_limits"`
+	Autostart        bool             `json:"autostart"`
+	FirewallPort     string           `json:"firewall_port"`
+	// Events active ou désactive un type d'événement par son nom
+	// ("song", "tts", "video", ...). Absent d'une clé == activé.
+	Events map[string]bool `json:"events"`
+}
+
+// Default retourne la configuration embarquée utilisée quand le fichier de
+// config est absent ou invalide.
+func Default() *Config {
+	return &Config{
+		Server: constant.IP_ADDR_SERVER,
+		ReconnectBackoff: ReconnectBackoff{
+			BaseMillis: 500,
+			CapSeconds: 30,
+		},
+		TTS: TTS{
+			DefaultLang: "fr-FR",
+		},
+		QueueLimits: QueueLimits{
+			QueueLimit:            50,
+			RequestsPerMinute:     3,
+			VoteSkipRatio:         0.4,
+			VoteSkipWindowSeconds: 45,
+		},
+		Autostart:    true,
+		FirewallPort: constant.FIREWALL_PORT,
+		Events:       map[string]bool{},
+	}
+}
+
+// EventEnabled indique si event doit être traité. Un événement sans entrée
+// explicite est activé par défaut.
+func (c *Config) EventEnabled(event string) bool {
+	if c == nil || c.Events == nil {
+		return true
+	}
+	enabled, ok := c.Events[event]
+	if !ok {
+		return true
+	}
+	return enabled
+}
+
+// VoteSkipWindow convertit QueueLimits.VoteSkipWindowSeconds en time.Duration.
+func (c *Config) VoteSkipWindow() time.Duration {
+	return time.Duration(c.QueueLimits.VoteSkipWindowSeconds) * time.Second
+}
+
+var current atomic.Pointer[Config]
+
+func init() {
+	current.Store(Default())
+}
+
+// Current retourne la configuration active. Toujours non-nil: au pire la
+// valeur par défaut embarquée.
+func Current() *Config {
+	return current.Load()
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Path retourne l'emplacement attendu du fichier de config:
+// %ProgramData%/live_chat/config.json sous Windows, équivalent XDG ailleurs.
+func Path() (string, error) {
+	if runtime.GOOS == "windows" {
+		base := os.Getenv("ProgramData")
+		if base == "" {
+			base = `C:\ProgramData`
+		}
+		return filepath.Join(base, "live_chat", "config.json"), nil
+	}
+
+	if base := os.Getenv("XDG_CONFIG_HOME"); base != "" {
+		return filepath.Join(base, "live_chat", "config.json"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(home, "Library", "Application Support", "live_chat", "config.json"), nil
+	}
+	return filepath.Join(home, ".config", "live_chat", "config.json"), nil
+}
+
+// Load lit le fichier de config (en écrivant le modèle par défaut s'il est
+// absent), puis surveille ses changements en arrière-plan pour recharger
+// Current() à chaud. À appeler une fois au démarrage.
+func Load() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := writeTemplate(path); err != nil {
+			return err
+		}
+		log.Println("config: modèle écrit dans", path)
+	}
+
+	if err := reload(path); err != nil {
+		log.Println("config: lecture initiale:", err)
+	}
+
+	go watch(path)
+	return nil
+}
+
+func reload(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	cfg := Default()
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return err
+	}
+
+	current.Store(cfg)
+	log.Println("config: rechargée depuis", path)
+	return nil
+}
+
+func writeTemplate(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(Default(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// watch surveille le dossier contenant path (pas le fichier lui-même, pour
+// survivre aux éditeurs qui remplacent le fichier plutôt que de l'écrire en
+// place) et recharge Current() à chaque écriture/création de path.
+func watch(path string) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Println("config: fsnotify indisponible:", err)
+		return
+	}
+	defer watcher.Close()
+
+	dir := filepath.Dir(path)
+	if err := watcher.Add(dir); err != nil {
+		log.Println("config: surveillance de", dir, "impossible:", err)
+		return
+	}
+
+	for {
+		select {
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(ev.Name) != filepath.Clean(path) {
+				continue
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			// Laisse le temps à l'écriture en cours de se terminer avant de relire.
+			time.Sleep(50 * time.Millisecond)
+			if err := reload(path); err != nil {
+				log.Println("config: rechargement:", err)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Println("config: fsnotify:", err)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package constant
+
+// IP_ADDR_SERVER est l'adresse par défaut du serveur live_chat, reprise par
+// config.Default quand config.json ne fixe pas "server".
+const IP_ADDR_SERVER = "127.0.0.1:3030"
+
+// FIREWALL_PORT est le port local à ouvrir dans le pare-feu pour les
+// dashboards/serveurs embarqués du client (ex: celui de la file d'attente).
+// Distinct du port du serveur live_chat (3030) pour que le dashboard de la
+// file d'attente ne se batte pas avec lui pour le même ListenAndServe.
+const FIREWALL_PORT = "3031"
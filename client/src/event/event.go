@@ -2,11 +2,16 @@ package event
 
 import (
 	"fmt"
-	"live_chat/src/constant"
+	"live_chat/src/config"
+	"live_chat/src/player"
+	"live_chat/src/queue"
 	"log"
+	"net"
 	"net/http"
+	"os"
 	"os/exec"
 	"runtime"
+	"strconv"
 	"sync"
 	"time"
 
@@ -14,20 +19,127 @@ import (
 	"github.com/hajimehoshi/go-mp3"
 )
 
+// fetchHTTPClient ne borne que l'établissement de la connexion et la
+// réception des en-têtes, pas la lecture du corps: un MP3 se lit au fil de
+// la lecture (waitAndClose), qui peut légitimement durer plus longtemps
+// qu'un délai raisonnable de connexion. Ça évite qu'un serveur distant qui
+// n'accepte jamais la connexion ne bloque indéfiniment la goroutine de
+// lecture de la file d'attente.
+var fetchHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext:           (&net.Dialer{Timeout: 10 * time.Second}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+	},
+}
+
 func DispatchEvent(json map[string]string) {
+	if !config.Current().EventEnabled(json["event"]) {
+		log.Println("event désactivé par la config:", json["event"])
+		return
+	}
+
 	switch json["event"] {
 	case "browser_backend":
 		if _, ok := json["url"]; ok {
-			openBrowser("http://" + constant.IP_ADDR_SERVER + json["url"])
+			openBrowser("http://" + config.Current().Server + json["url"])
 		} else {
 			log.Println("no url found")
 		}
 	case "song":
-		if _, ok := json["url"]; ok {
-			playSong("http://" + constant.IP_ADDR_SERVER + json["url"])
+		if url, ok := json["url"]; ok {
+			enqueue(queue.Item{
+				Type:        "song",
+				Payload:     "http://" + config.Current().Server + url,
+				RequesterID: requesterID(json),
+			})
+		} else {
+			log.Println("no url found")
+		}
+	case "video", "stream":
+		if url, ok := json["url"]; ok {
+			enqueue(queue.Item{
+				Type:        json["event"],
+				Payload:     url,
+				RequesterID: requesterID(json),
+			})
+		} else {
+			log.Println("no url found")
+		}
+	case "playlist":
+		if url, ok := json["url"]; ok {
+			enqueue(queue.Item{
+				Type:        "playlist",
+				Payload:     url,
+				RequesterID: requesterID(json),
+			})
 		} else {
 			log.Println("no url found")
 		}
+	case "tts":
+		text, ok := json["text"]
+		if !ok {
+			log.Println("tts: no text found")
+			break
+		}
+		lang := json["lang"]
+		if lang == "" {
+			lang = config.Current().TTS.DefaultLang
+		}
+		enqueue(queue.Item{
+			Type:        "tts",
+			Payload:     text,
+			Lang:        lang,
+			Voice:       json["voice"],
+			RequesterID: requesterID(json),
+		})
+	case "voteskip":
+		if queue.VoteSkip(requesterID(json)) {
+			log.Println("voteskip: seuil atteint, passage à l'élément suivant")
+			notifyServer(map[string]string{"event": "voteskip_result", "skipped": "true"})
+		}
+	case "pause":
+		if !player.Available() {
+			log.Println("pause: mpv indisponible")
+			break
+		}
+		if err := player.Pause(json["state"] != "false"); err != nil {
+			log.Println("pause:", err)
+		}
+	case "stop":
+		if !player.Available() {
+			log.Println("stop: mpv indisponible")
+			break
+		}
+		if err := player.Stop(); err != nil {
+			log.Println("stop:", err)
+		}
+	case "volume":
+		if !player.Available() {
+			log.Println("volume: mpv indisponible")
+			break
+		}
+		percent, err := strconv.Atoi(json["value"])
+		if err != nil {
+			log.Println("volume: valeur invalide:", json["value"])
+			break
+		}
+		if err := player.Volume(percent); err != nil {
+			log.Println("volume:", err)
+		}
+	case "seek":
+		if !player.Available() {
+			log.Println("seek: mpv indisponible")
+			break
+		}
+		offset, err := strconv.ParseFloat(json["value"], 64)
+		if err != nil {
+			log.Println("seek: valeur invalide:", json["value"])
+			break
+		}
+		if err := player.Seek(offset); err != nil {
+			log.Println("seek:", err)
+		}
 	case "browser_raw":
 		if _, ok := json["url"]; ok {
 			openBrowser(json["url"])
@@ -36,10 +148,10 @@ func DispatchEvent(json map[string]string) {
 		}
 	case "combination":
 		if _, ok := json["audio"]; ok {
-			playSong("http://" + constant.IP_ADDR_SERVER + json["url"])
+			playSong("http://" + config.Current().Server + json["url"])
 		}
 		if _, ok := json["url"]; ok {
-			openBrowser("http://" + constant.IP_ADDR_SERVER + json["url"])
+			openBrowser("http://" + config.Current().Server + json["url"])
 		}
 		if _, ok := json["url_raw"]; ok {
 			openBrowser(json["url"])
@@ -49,6 +161,23 @@ func DispatchEvent(json map[string]string) {
 	}
 }
 
+// requesterID identifie le demandeur d'un événement pour la limitation de
+// débit et le voteskip de la file d'attente.
+func requesterID(json map[string]string) string {
+	if id, ok := json["requester_id"]; ok && id != "" {
+		return id
+	}
+	return "anonymous"
+}
+
+// enqueue place item dans la file d'attente par défaut et journalise les
+// refus (quota dépassé, file pleine).
+func enqueue(item queue.Item) {
+	if err := queue.Enqueue(item); err != nil {
+		log.Println("queue.Enqueue:", err)
+	}
+}
+
 func openBrowser(url string) {
 	var cmd *exec.Cmd
 	switch runtime.GOOS {
@@ -89,39 +218,145 @@ func InitOtoContext() {
 	<-readyChan
 }
 
-func playSong(url string) {
+func newOtoPlayer(decoded *mp3.Decoder) *oto.Player {
+	if otoCtx == nil {
+		mu.Lock()
+		if otoCtx == nil {
+			InitOtoContext()
+		}
+		mu.Unlock()
+	}
+	return otoCtx.NewPlayer(decoded)
+}
+
+// activePlayer est le lecteur oto en cours, utilisé par stopOtoPlayback
+// pour interrompre la lecture depuis le Skip/voteskip de la file d'attente.
+var (
+	activePlayer *oto.Player
+	activeMu     sync.Mutex
+)
+
+func trackOtoPlayer(p *oto.Player) {
+	activeMu.Lock()
+	activePlayer = p
+	activeMu.Unlock()
+}
+
+func clearOtoPlayer(p *oto.Player) {
+	activeMu.Lock()
+	if activePlayer == p {
+		activePlayer = nil
+	}
+	activeMu.Unlock()
+}
+
+// stopOtoPlayback interrompt le lecteur oto en cours, s'il y en a un. C'est
+// l'équivalent oto de player.Stop() pour la file d'attente.
+func stopOtoPlayback() {
+	activeMu.Lock()
+	p := activePlayer
+	activeMu.Unlock()
+	if p != nil {
+		p.Pause()
+	}
+}
+
+// waitAndClose bloque jusqu'à la fin de la lecture de p puis le ferme.
+func waitAndClose(p *oto.Player) {
+	for p.IsPlaying() {
+		time.Sleep(time.Millisecond)
+	}
+	if err := p.Close(); err != nil {
+		log.Println("player.Close failed:", err)
+	}
+	clearOtoPlayer(p)
+}
+
+// fetchMP3 télécharge url et ouvre un décodeur MP3 sur le corps de la
+// réponse ; l'appelant est responsable de fermer resp.Body une fois la
+// lecture terminée.
+func fetchMP3(url string) (*http.Response, *mp3.Decoder, error) {
 	fmt.Println("Downloading sound from:", url)
-	// Télécharger le fichier audio depuis l'URL
-	resp, err := http.Get(url)
+	resp, err := fetchHTTPClient.Get(url)
 	if err != nil {
-		log.Fatal(err)
+		return nil, nil, err
 	}
-
 	if resp.StatusCode != http.StatusOK {
-		log.Fatalf("bad status: %s", resp.Status)
+		resp.Body.Close()
+		return nil, nil, fmt.Errorf("bad status: %s", resp.Status)
 	}
+	decoded, err := mp3.NewDecoder(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return nil, nil, fmt.Errorf("mp3.NewDecoder failed: %w", err)
+	}
+	return resp, decoded, nil
+}
 
-	decodedMp3, err := mp3.NewDecoder(resp.Body)
+// openLocalMP3 ouvre un décodeur MP3 sur un fichier local ; l'appelant est
+// responsable de fermer le fichier retourné une fois la lecture terminée.
+func openLocalMP3(path string) (*os.File, *mp3.Decoder, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	decoded, err := mp3.NewDecoder(f)
 	if err != nil {
-		panic("mp3.NewDecoder failed: " + err.Error())
+		f.Close()
+		return nil, nil, fmt.Errorf("mp3.NewDecoder failed: %w", err)
 	}
+	return f, decoded, nil
+}
 
-	if otoCtx == nil {
-		mu.Lock()
-		InitOtoContext()
-		mu.Unlock()
+// playSong joue url via le pipeline oto+go-mp3, sans passer par mpv ni par
+// la file d'attente, que mpv soit disponible ou non. Non bloquant, utilisé
+// par les événements qui jouent plusieurs sons en parallèle (ex:
+// "combination").
+func playSong(url string) {
+	resp, decoded, err := fetchMP3(url)
+	if err != nil {
+		log.Println("playSong:", err)
+		return
 	}
 
-	player := otoCtx.NewPlayer(decodedMp3)
-	player.Play()
+	p := newOtoPlayer(decoded)
+	trackOtoPlayer(p)
+	p.Play()
 	go func() {
-		for player.IsPlaying() {
-			time.Sleep(time.Millisecond)
-		}
-		err = player.Close()
-		if err != nil {
-			panic("player.Close failed: " + err.Error())
-		}
+		waitAndClose(p)
 		resp.Body.Close()
 	}()
 }
+
+// playSongSync est l'équivalent bloquant de playSong, utilisé par la file
+// d'attente pour sérialiser la lecture en repli oto.
+func playSongSync(url string) {
+	resp, decoded, err := fetchMP3(url)
+	if err != nil {
+		log.Println("playSongSync:", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	p := newOtoPlayer(decoded)
+	trackOtoPlayer(p)
+	p.Play()
+	waitAndClose(p)
+}
+
+// playLocalFileSync est l'équivalent bloquant de playSong pour un clip déjà
+// présent sur disque (ex: un clip TTS mis en cache), utilisé par la file
+// d'attente quand mpv est indisponible.
+func playLocalFileSync(path string) {
+	f, decoded, err := openLocalMP3(path)
+	if err != nil {
+		log.Println("playLocalFileSync:", err)
+		return
+	}
+	defer f.Close()
+
+	p := newOtoPlayer(decoded)
+	trackOtoPlayer(p)
+	p.Play()
+	waitAndClose(p)
+}
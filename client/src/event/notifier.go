@@ -0,0 +1,24 @@
+package event
+
+import "log"
+
+// notify pousse un message vers le serveur distant (résultats de voteskip,
+// erreurs TTS, ...). Injecté par main via SetNotifier plutôt qu'importé
+// directement, pour éviter un cycle d'import avec le package websocket (qui
+// appelle DispatchEvent).
+var notify func(v interface{}) error
+
+// SetNotifier enregistre la fonction utilisée pour répondre au serveur,
+// typiquement websocket.(*Client).SendJSON.
+func SetNotifier(fn func(v interface{}) error) {
+	notify = fn
+}
+
+func notifyServer(v interface{}) {
+	if notify == nil {
+		return
+	}
+	if err := notify(v); err != nil {
+		log.Println("notify:", err)
+	}
+}
@@ -0,0 +1,89 @@
+package event
+
+import (
+	"live_chat/src/player"
+	"live_chat/src/queue"
+	"live_chat/src/tts"
+	"log"
+)
+
+// PlayQueueItem joue un élément de la file d'attente via mpv quand il est
+// disponible, sinon via le repli oto, et ferme le canal retourné une fois la
+// lecture terminée (naturellement ou après StopPlayback). C'est la
+// queue.PlayFunc branchée sur le Manager par défaut dans main.go.
+func PlayQueueItem(item queue.Item) <-chan struct{} {
+	done := make(chan struct{})
+
+	switch item.Type {
+	case "song", "video", "stream", "playlist":
+		mode := "replace"
+		if item.Type == "playlist" {
+			mode = "append-play"
+		}
+		go playQueuedMedia(item.Payload, mode, done)
+	case "tts":
+		go playQueuedTTS(item.Payload, item.Lang, item.Voice, done)
+	default:
+		log.Println("queue: type d'élément inconnu:", item.Type)
+		close(done)
+	}
+
+	return done
+}
+
+func playQueuedMedia(url, mode string, done chan struct{}) {
+	defer close(done)
+
+	if player.Available() {
+		doneMpv, err := player.LoadFile(url, mode)
+		if err != nil {
+			log.Println("player.LoadFile:", err)
+			return
+		}
+		<-doneMpv
+		return
+	}
+
+	log.Println("mpv indisponible, repli sur oto pour:", url)
+	playSongSync(url)
+}
+
+func playQueuedTTS(text, lang, voice string, done chan struct{}) {
+	defer close(done)
+
+	path, format, err := tts.Speak(text, lang, voice)
+	if err != nil {
+		log.Println("tts.Speak:", err)
+		notifyServer(map[string]string{"event": "tts_error", "message": err.Error()})
+		return
+	}
+
+	if player.Available() {
+		doneMpv, err := player.LoadFile(path, "append-play")
+		if err != nil {
+			log.Println("player.LoadFile:", err)
+			return
+		}
+		<-doneMpv
+		return
+	}
+
+	if format != "mp3" {
+		log.Printf("tts: repli oto impossible pour le format %q, mpv est requis", format)
+		return
+	}
+	playLocalFileSync(path)
+}
+
+// StopPlayback interrompt la lecture en cours (mpv ou repli oto). C'est la
+// fonction stop branchée sur le Manager par défaut, utilisée par Skip() et
+// par un voteskip réussi.
+func StopPlayback() {
+	if player.Available() {
+		if err := player.Stop(); err != nil {
+			log.Println("player.Stop:", err)
+		}
+		return
+	}
+	stopOtoPlayback()
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package player
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// ipcPath retourne le chemin du socket unix utilisé pour l'IPC mpv.
+func ipcPath() string {
+	return filepath.Join(os.TempDir(), ipcSocketName+".sock")
+}
+
+func dialIPC(path string) (net.Conn, error) {
+	return net.Dial("unix", path)
+}
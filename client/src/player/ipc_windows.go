@@ -0,0 +1,18 @@
+//go:build windows
+
+package player
+
+import (
+	"net"
+
+	"github.com/Microsoft/go-winio"
+)
+
+// ipcPath retourne le nom du named pipe utilisé pour l'IPC mpv.
+func ipcPath() string {
+	return `\\.\pipe\` + ipcSocketName
+}
+
+func dialIPC(path string) (net.Conn, error) {
+	return winio.DialPipe(path, nil)
+}
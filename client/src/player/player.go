@@ -0,0 +1,213 @@
+// Package player gère un processus mpv long-vivant piloté via son socket
+// d'IPC JSON, afin de lire aussi bien des MP3 courts que des flux HLS/m3u8
+// ou des vidéos. Quand mpv n'est pas disponible sur la machine, les appelants
+// doivent se replier sur le pipeline oto historique.
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const ipcSocketName = "live_chat-mpv-ipc"
+
+var (
+	mu        sync.Mutex
+	cmd       *exec.Cmd
+	conn      net.Conn
+	nextReqID int64
+	available atomic.Bool
+	// pending est le canal de fin de lecture du dernier LoadFile en date.
+	// Il est remplacé à chaque appel (sous mu) et fermé quand mpv redevient
+	// idle, ce qui évite qu'un signal destiné à un élément ne réveille par
+	// erreur l'attente d'un autre (cf. playlists à plusieurs pistes).
+	pending chan struct{}
+)
+
+type ipcCommand struct {
+	Command   []interface{} `json:"command"`
+	RequestID int64         `json:"request_id,omitempty"`
+}
+
+type ipcResponse struct {
+	Error     string      `json:"error"`
+	Data      interface{} `json:"data"`
+	RequestID int64       `json:"request_id"`
+	Event     string      `json:"event"`
+	Name      string      `json:"name"`
+}
+
+// Available signale si le processus mpv a démarré et répond sur son socket IPC.
+func Available() bool {
+	return available.Load()
+}
+
+// Start lance mpv en mode idle et ouvre la connexion IPC. exDir est le
+// répertoire de l'exécutable, dans lequel setup.Install copie le
+// dossier mpv/ embarqué. Un appel répété est un no-op.
+func Start(exDir string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if cmd != nil {
+		return nil
+	}
+
+	mpvPath := mpvBinary(exDir)
+	sock := ipcPath()
+
+	c := exec.Command(mpvPath,
+		"--idle=yes",
+		"--force-window=no",
+		"--input-ipc-server="+sock,
+		"--no-terminal",
+	)
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("démarrage de mpv: %w", err)
+	}
+
+	var dialErr error
+	var ipcConn net.Conn
+	for i := 0; i < 50; i++ {
+		ipcConn, dialErr = dialIPC(sock)
+		if dialErr == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if dialErr != nil {
+		_ = c.Process.Kill()
+		return fmt.Errorf("connexion au socket IPC de mpv: %w", dialErr)
+	}
+
+	cmd = c
+	conn = ipcConn
+	available.Store(true)
+	go readLoop(ipcConn)
+
+	// idle-active nous indique quand mpv a fini tout ce qu'il avait en file
+	// (une piste seule comme une playlist entière), contrairement à
+	// end-file qui est émis une fois par piste.
+	if err := sendCommand("observe_property", 1, "idle-active"); err != nil {
+		log.Println("mpv IPC: observe_property idle-active:", err)
+	}
+	return nil
+}
+
+// mpvBinary retourne le chemin vers l'exécutable mpv embarqué s'il existe,
+// sinon se rabat sur le binaire du PATH.
+func mpvBinary(exDir string) string {
+	name := "mpv"
+	if runtime.GOOS == "windows" {
+		name = "mpv.exe"
+	}
+	bundled := filepath.Join(exDir, "mpv", name)
+	if _, err := exec.LookPath(bundled); err == nil {
+		return bundled
+	}
+	return name
+}
+
+func readLoop(c net.Conn) {
+	scanner := bufio.NewScanner(c)
+	for scanner.Scan() {
+		var resp ipcResponse
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			continue
+		}
+		if resp.Error != "" && resp.Error != "success" {
+			log.Println("mpv IPC:", resp.Error)
+		}
+		if resp.Event == "property-change" && resp.Name == "idle-active" {
+			if idle, ok := resp.Data.(bool); ok && idle {
+				mu.Lock()
+				if pending != nil {
+					close(pending)
+					pending = nil
+				}
+				mu.Unlock()
+			}
+		}
+	}
+	available.Store(false)
+
+	// mpv est mort (crash, kill externe, ...) avant d'avoir pu signaler
+	// idle-active: débloquer l'élément en cours pour que le Manager se
+	// replie sur oto plutôt que d'attendre indéfiniment un signal qui ne
+	// viendra jamais.
+	mu.Lock()
+	if pending != nil {
+		close(pending)
+		pending = nil
+	}
+	mu.Unlock()
+}
+
+func sendCommand(args ...interface{}) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if conn == nil {
+		return fmt.Errorf("mpv n'est pas démarré")
+	}
+
+	nextReqID++
+	payload, err := json.Marshal(ipcCommand{Command: args, RequestID: nextReqID})
+	if err != nil {
+		return err
+	}
+	payload = append(payload, '\n')
+
+	_, err = conn.Write(payload)
+	return err
+}
+
+// LoadFile demande à mpv de jouer url. mode doit valoir "replace" (coupe ce
+// qui joue), "append" (mise en file) ou "append-play" (mise en file, lance
+// tout de suite si rien ne joue). Le canal retourné est propre à cet appel
+// et se ferme quand mpv redevient idle, qu'il s'agisse d'un fichier seul ou
+// d'une playlist à plusieurs pistes.
+func LoadFile(url string, mode string) (<-chan struct{}, error) {
+	if mode == "" {
+		mode = "replace"
+	}
+
+	done := make(chan struct{})
+	mu.Lock()
+	pending = done
+	mu.Unlock()
+
+	if err := sendCommand("loadfile", url, mode); err != nil {
+		return nil, err
+	}
+	return done, nil
+}
+
+// Pause met en pause ou reprend la lecture en cours.
+func Pause(paused bool) error {
+	return sendCommand("set_property", "pause", paused)
+}
+
+// Stop arrête la lecture en cours et vide la playlist mpv.
+func Stop() error {
+	return sendCommand("stop")
+}
+
+// Volume fixe le volume mpv entre 0 et 100.
+func Volume(percent int) error {
+	return sendCommand("set_property", "volume", percent)
+}
+
+// Seek déplace la position de lecture de offset secondes (relatif).
+func Seek(offsetSeconds float64) error {
+	return sendCommand("seek", offsetSeconds, "relative")
+}
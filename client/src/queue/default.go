@@ -0,0 +1,34 @@
+package queue
+
+var defaultManager *Manager
+
+// Init crée le Manager par défaut utilisé par Enqueue, VoteSkip,
+// CurrentStatus et le serveur HTTP. À appeler une fois au démarrage.
+func Init(cfg Config, play PlayFunc, stop func()) *Manager {
+	defaultManager = NewManager(cfg, play, stop)
+	return defaultManager
+}
+
+// Enqueue ajoute item à la file par défaut.
+func Enqueue(item Item) error {
+	if defaultManager == nil {
+		return ErrNotStarted
+	}
+	return defaultManager.Enqueue(item)
+}
+
+// VoteSkip enregistre un vote de passage sur la file par défaut.
+func VoteSkip(requesterID string) bool {
+	if defaultManager == nil {
+		return false
+	}
+	return defaultManager.VoteSkip(requesterID)
+}
+
+// CurrentStatus retourne l'état de la file par défaut.
+func CurrentStatus() Status {
+	if defaultManager == nil {
+		return Status{}
+	}
+	return defaultManager.Status()
+}
@@ -0,0 +1,258 @@
+// Package queue sérialise la lecture des demandes (chanson, TTS, vidéo)
+// dans une file FIFO unique, avec limitation de débit par demandeur et un
+// voteskip pour passer l'élément en cours.
+package queue
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Item décrit une demande de lecture en attente ou en cours.
+type Item struct {
+	Type        string        `json:"type"`
+	Payload     string        `json:"payload"`
+	Lang        string        `json:"lang,omitempty"`
+	Voice       string        `json:"voice,omitempty"`
+	RequesterID string        `json:"requester_id"`
+	EnqueuedAt  time.Time     `json:"enqueued_at"`
+	Duration    time.Duration `json:"duration,omitempty"`
+}
+
+// PlayFunc joue item et retourne un canal fermé une fois la lecture
+// terminée, que ce soit naturellement ou après un Stop/Skip.
+type PlayFunc func(Item) <-chan struct{}
+
+// Config regroupe les limites appliquées par un Manager.
+type Config struct {
+	QueueLimit        int
+	RequestsPerMinute int
+	VoteSkipRatio     float64
+	VoteSkipWindow    time.Duration
+}
+
+// DefaultConfig reflète les valeurs par défaut demandées: 40% de votes sur
+// une fenêtre de 45s font passer l'élément en cours.
+func DefaultConfig() Config {
+	return Config{
+		QueueLimit:        50,
+		RequestsPerMinute: 3,
+		VoteSkipRatio:     0.4,
+		VoteSkipWindow:    45 * time.Second,
+	}
+}
+
+var (
+	ErrQueueFull   = errors.New("file d'attente pleine")
+	ErrRateLimited = errors.New("trop de demandes, réessayez dans une minute")
+	ErrNotStarted  = errors.New("la file d'attente n'est pas initialisée")
+)
+
+// Manager maintient la file FIFO et orchestre sa lecture séquentielle.
+type Manager struct {
+	cfg  Config
+	play PlayFunc
+	stop func()
+
+	mu              sync.Mutex
+	pending         []Item
+	current         *Item
+	voteWindowStart time.Time
+	votes           map[string]bool
+	// activeRequesters associe chaque demandeur/votant vu à sa dernière
+	// activité, pour approximer une audience récente plutôt qu'un décompte
+	// qui ne ferait que croître sur toute la durée de vie du client.
+	activeRequesters map[string]time.Time
+	requestTimes     map[string][]time.Time
+
+	incoming chan struct{}
+}
+
+// NewManager crée un Manager et démarre sa boucle de lecture en arrière-plan.
+// play est appelé pour chaque élément dequeued; stop doit interrompre la
+// lecture en cours (utilisé par Skip et par un voteskip réussi).
+func NewManager(cfg Config, play PlayFunc, stop func()) *Manager {
+	m := &Manager{
+		cfg:              cfg,
+		play:             play,
+		stop:             stop,
+		votes:            make(map[string]bool),
+		activeRequesters: make(map[string]time.Time),
+		requestTimes:     make(map[string][]time.Time),
+		incoming:         make(chan struct{}, 1),
+	}
+	go m.run()
+	return m
+}
+
+// Enqueue ajoute item en fin de file, sous réserve du quota par minute du
+// demandeur et de la taille maximale de la file.
+func (m *Manager) Enqueue(item Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.allow(item.RequesterID) {
+		return ErrRateLimited
+	}
+	if len(m.pending) >= m.cfg.QueueLimit {
+		return ErrQueueFull
+	}
+
+	item.EnqueuedAt = time.Now()
+	m.pending = append(m.pending, item)
+	m.activeRequesters[item.RequesterID] = item.EnqueuedAt
+
+	select {
+	case m.incoming <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// allow applique la limite RequestsPerMinute par demandeur.
+func (m *Manager) allow(requesterID string) bool {
+	now := time.Now()
+	cutoff := now.Add(-time.Minute)
+
+	kept := m.requestTimes[requesterID][:0]
+	for _, t := range m.requestTimes[requesterID] {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) >= m.cfg.RequestsPerMinute {
+		m.requestTimes[requesterID] = kept
+		return false
+	}
+	m.requestTimes[requesterID] = append(kept, now)
+	return true
+}
+
+func (m *Manager) run() {
+	for {
+		m.mu.Lock()
+		if len(m.pending) == 0 {
+			m.mu.Unlock()
+			<-m.incoming
+			continue
+		}
+		item := m.pending[0]
+		m.pending = m.pending[1:]
+		m.current = &item
+		m.votes = make(map[string]bool)
+		m.voteWindowStart = time.Now()
+		m.mu.Unlock()
+
+		<-m.play(item)
+
+		m.mu.Lock()
+		m.current = nil
+		m.mu.Unlock()
+	}
+}
+
+// VoteSkip enregistre un vote de requesterID pour passer l'élément en
+// cours. Quand le ratio de votants distincts dans la fenêtre atteint
+// VoteSkipRatio, la lecture en cours est arrêtée et true est retourné. Un
+// votant isolé ne peut jamais déclencher le skip seul: il en faut au moins
+// deux, même si le ratio configuré serait techniquement atteint avec un
+// seul (cas d'une audience connue de taille 1).
+func (m *Manager) VoteSkip(requesterID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.current == nil {
+		return false
+	}
+
+	now := time.Now()
+	if now.Sub(m.voteWindowStart) > m.cfg.VoteSkipWindow {
+		m.votes = make(map[string]bool)
+		m.voteWindowStart = now
+	}
+	m.votes[requesterID] = true
+	// Un votant compte pour l'audience même s'il n'a encore rien demandé.
+	m.activeRequesters[requesterID] = now
+
+	if len(m.votes) > 1 && float64(len(m.votes))/float64(m.audienceLocked()) >= m.cfg.VoteSkipRatio {
+		m.votes = make(map[string]bool)
+		if m.stop != nil {
+			m.stop()
+		}
+		return true
+	}
+	return false
+}
+
+// audienceLocked approxime la taille de l'audience active par le nombre de
+// demandeurs et votants distincts vus au cours de la fenêtre VoteSkipWindow,
+// faute d'un décompte réel des viewers côté client. Se baser sur une
+// fenêtre récente plutôt que sur un cumul depuis le démarrage évite que le
+// seuil ne devienne inatteignable sur un stream de longue durée; les
+// entrées expirées sont purgées au passage.
+func (m *Manager) audienceLocked() int {
+	cutoff := time.Now().Add(-m.cfg.VoteSkipWindow)
+	n := 0
+	for id, lastSeen := range m.activeRequesters {
+		if lastSeen.Before(cutoff) {
+			delete(m.activeRequesters, id)
+			continue
+		}
+		n++
+	}
+	if n > 0 {
+		return n
+	}
+	return 1
+}
+
+// Skip arrête immédiatement l'élément en cours sans passer par un vote
+// (utilisé par le dashboard HTTP).
+func (m *Manager) Skip() {
+	m.mu.Lock()
+	hasCurrent := m.current != nil
+	m.mu.Unlock()
+
+	if hasCurrent && m.stop != nil {
+		m.stop()
+	}
+}
+
+// Status est l'état de la file exposé par l'API JSON et le dashboard HTML.
+type Status struct {
+	Current   *Item  `json:"current"`
+	Pending   []Item `json:"pending"`
+	VoteCount int    `json:"vote_count"`
+	VoteGoal  int    `json:"vote_goal"`
+}
+
+// Status retourne un instantané cohérent de la file.
+func (m *Manager) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// VoteGoal est le nombre de votants distincts exigé par VoteSkip: au
+	// moins deux, et assez pour atteindre VoteSkipRatio de l'audience.
+	audience := m.audienceLocked()
+	goal := 2
+	for goal < audience && float64(goal)/float64(audience) < m.cfg.VoteSkipRatio {
+		goal++
+	}
+
+	pending := make([]Item, len(m.pending))
+	copy(pending, m.pending)
+
+	var current *Item
+	if m.current != nil {
+		c := *m.current
+		current = &c
+	}
+
+	return Status{
+		Current:   current,
+		Pending:   pending,
+		VoteCount: len(m.votes),
+		VoteGoal:  goal,
+	}
+}
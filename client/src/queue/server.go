@@ -0,0 +1,73 @@
+package queue
+
+import (
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+)
+
+// Serve démarre le petit serveur HTTP du dashboard de file d'attente sur
+// addr (ex: ":3031"): /queue et /now en JSON, /skip en POST, et une page
+// HTML minimale à la racine. Bloquant, à appeler dans sa propre goroutine.
+func Serve(addr string, m *Manager) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/queue", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, m.Status().Pending)
+	})
+
+	mux.HandleFunc("/now", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, m.Status())
+	})
+
+	mux.HandleFunc("/skip", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST requis", http.StatusMethodNotAllowed)
+			return
+		}
+		m.Skip()
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := dashboardTemplate.Execute(w, m.Status()); err != nil {
+			log.Println("queue: rendu du dashboard:", err)
+		}
+	})
+
+	log.Println("Serveur de file d'attente à l'écoute sur", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Println("queue.Serve:", err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println("queue: encodage JSON:", err)
+	}
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html lang="fr">
+<head><meta charset="utf-8"><title>File d'attente live_chat</title></head>
+<body>
+<h1>En cours</h1>
+{{if .Current}}
+<p>{{.Current.Type}} — {{.Current.Payload}} (demandé par {{.Current.RequesterID}})</p>
+<p>Votes pour passer : {{.VoteCount}}/{{.VoteGoal}}</p>
+{{else}}
+<p>Rien en cours de lecture.</p>
+{{end}}
+<h1>À venir</h1>
+<ol>
+{{range .Pending}}<li>{{.Type}} — {{.Payload}} (demandé par {{.RequesterID}})</li>{{end}}
+</ol>
+</body>
+</html>
+`))
@@ -0,0 +1,55 @@
+//go:build darwin
+
+package setup
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+type darwinAutostart struct{}
+
+func newAutostart() Autostart { return darwinAutostart{} }
+
+const launchAgentTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>com.live_chat</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+</dict>
+</plist>
+`
+
+// Enable écrit un LaunchAgent avec RunAtLoad=true et le charge immédiatement.
+func (darwinAutostart) Enable(execPath string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	agentDir := filepath.Join(home, "Library", "LaunchAgents")
+	if err := os.MkdirAll(agentDir, 0755); err != nil {
+		return err
+	}
+
+	plistPath := filepath.Join(agentDir, "com.live_chat.plist")
+	content := fmt.Sprintf(launchAgentTemplate, execPath)
+	if err := os.WriteFile(plistPath, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("launchctl", "load", plistPath)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("launchctl: %w: %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+//go:build linux
+
+package setup
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+type linuxAutostart struct{}
+
+func newAutostart() Autostart { return linuxAutostart{} }
+
+const systemdUnitTemplate = `[Unit]
+Description=live_chat
+
+[Service]
+ExecStart=%s
+Restart=on-failure
+
+[Install]
+WantedBy=default.target
+`
+
+// Enable écrit une unité systemd --user et la démarre/active immédiatement.
+func (linuxAutostart) Enable(execPath string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	unitDir := filepath.Join(home, ".config", "systemd", "user")
+	if err := os.MkdirAll(unitDir, 0755); err != nil {
+		return err
+	}
+
+	unitPath := filepath.Join(unitDir, "live_chat.service")
+	content := fmt.Sprintf(systemdUnitTemplate, execPath)
+	if err := os.WriteFile(unitPath, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("systemctl", "--user", "enable", "--now", "live_chat.service")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("systemctl: %w: %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+//go:build windows
+
+package setup
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+type windowsAutostart struct{}
+
+func newAutostart() Autostart { return windowsAutostart{} }
+
+// Enable dépose un raccourci VBS dans le dossier de démarrage de
+// l'utilisateur et enregistre en plus une tâche planifiée "à la connexion",
+// en alternative si le dossier de démarrage est désactivé par une politique.
+func (windowsAutostart) Enable(execPath string) error {
+	if err := writeStartupVBS(execPath); err != nil {
+		return err
+	}
+
+	if err := registerScheduledTask(execPath); err != nil {
+		log.Println("schtasks:", err)
+	}
+	return nil
+}
+
+func writeStartupVBS(execPath string) error {
+	startupPath := filepath.Join(os.Getenv("APPDATA"), "Microsoft", "Windows", "Start Menu", "Programs", "Startup")
+	vbsContent := `Set WshShell = WScript.CreateObject("WScript.Shell")` + "\n" +
+		`WshShell.Run """` + vbsEscape(execPath) + `""", 0` + "\n" +
+		`Set WshShell = Nothing`
+
+	vbsPath := filepath.Join(startupPath, "start_live_chat.vbs")
+	return os.WriteFile(vbsPath, []byte(vbsContent), 0644)
+}
+
+// vbsEscape échappe les guillemets d'execPath en les doublant (la syntaxe
+// VBScript pour un guillemet littéral à l'intérieur d'une chaîne), seul
+// caractère qui casserait la citation du chemin passé à Run.
+func vbsEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, `""`)
+}
+
+// registerScheduledTask enregistre une tâche planifiée "à la connexion"
+// comme alternative au raccourci VBS, via schtasks.
+func registerScheduledTask(execPath string) error {
+	cmd := exec.Command("schtasks", "/Create", "/TN", "live_chat", "/TR", execPath, "/SC", "ONLOGON", "/F")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+//go:build darwin
+
+package setup
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+type darwinFirewall struct{}
+
+func newFirewall() Firewall { return darwinFirewall{} }
+
+const socketfilterfw = "/usr/libexec/ApplicationFirewall/socketfilterfw"
+
+// AllowPort autorise l'exécutable courant dans le pare-feu applicatif de
+// macOS, qui filtre par application plutôt que par port.
+func (darwinFirewall) AllowPort(_ string) error {
+	exPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	if out, err := exec.Command(socketfilterfw, "--add", exPath).CombinedOutput(); err != nil {
+		return fmt.Errorf("socketfilterfw --add: %w: %s", err, out)
+	}
+	if out, err := exec.Command(socketfilterfw, "--unblockapp", exPath).CombinedOutput(); err != nil {
+		return fmt.Errorf("socketfilterfw --unblockapp: %w: %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+//go:build linux
+
+package setup
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+type linuxFirewall struct{}
+
+func newFirewall() Firewall { return linuxFirewall{} }
+
+// AllowPort essaie ufw puis firewall-cmd, selon celui qui est installé.
+func (linuxFirewall) AllowPort(port string) error {
+	if _, err := exec.LookPath("ufw"); err == nil {
+		cmd := exec.Command("ufw", "allow", port+"/tcp")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("ufw: %w: %s", err, out)
+		}
+		return nil
+	}
+
+	if _, err := exec.LookPath("firewall-cmd"); err == nil {
+		cmd := exec.Command("firewall-cmd", "--add-port="+port+"/tcp", "--permanent")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("firewall-cmd: %w: %s", err, out)
+		}
+		if out, err := exec.Command("firewall-cmd", "--reload").CombinedOutput(); err != nil {
+			return fmt.Errorf("firewall-cmd --reload: %w: %s", err, out)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("ni ufw ni firewall-cmd n'ont été trouvés")
+}
@@ -0,0 +1,26 @@
+//go:build windows
+
+package setup
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+)
+
+type windowsFirewall struct{}
+
+func newFirewall() Firewall { return windowsFirewall{} }
+
+func (windowsFirewall) AllowPort(port string) error {
+	log.Println("Configuration du pare-feu Windows pour autoriser le port " + port + "...")
+	cmd := exec.Command("netsh", "advfirewall", "firewall", "add", "rule",
+		"name=live_chat", "dir=in", "action=allow", "protocol=TCP", "localport="+port)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("netsh: %w: %s", err, output)
+	}
+	log.Println("Règle de pare-feu pour le port " + port + " ajoutée avec succès.")
+	return nil
+}
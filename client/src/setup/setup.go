@@ -1,13 +1,109 @@
+// Package setup installe le client pour qu'il démarre automatiquement avec
+// la session et ouvre le port qu'il écoute dans le pare-feu local, avec une
+// implémentation par OS (Windows, Linux, macOS).
 package setup
 
 import (
+	"fmt"
 	"io"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"runtime"
 )
 
+// Autostart enregistre l'exécutable auprès du mécanisme de démarrage
+// automatique propre à l'OS courant.
+type Autostart interface {
+	Enable(execPath string) error
+}
+
+// NewAutostart retourne l'implémentation d'Autostart pour l'OS courant.
+func NewAutostart() Autostart {
+	return newAutostart()
+}
+
+// Firewall ouvre un port TCP dans le pare-feu local pour que des clients
+// distants (le dashboard de la file d'attente, par ex.) puissent l'atteindre.
+type Firewall interface {
+	AllowPort(port string) error
+}
+
+// NewFirewall retourne l'implémentation de Firewall pour l'OS courant.
+func NewFirewall() Firewall {
+	return newFirewall()
+}
+
+// DefaultInstallDir retourne le répertoire où le client doit être installé
+// pour démarrer automatiquement: Program Files sous Windows, Application
+// Support sous macOS, XDG_DATA_HOME (ou ~/.local/share) ailleurs.
+func DefaultInstallDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		base := os.Getenv("ProgramFiles")
+		if base == "" {
+			return "", fmt.Errorf("variable d'environnement ProgramFiles introuvable")
+		}
+		return filepath.Join(base, "live_chat"), nil
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "Application Support", "live_chat"), nil
+	default:
+		if base := os.Getenv("XDG_DATA_HOME"); base != "" {
+			return filepath.Join(base, "live_chat"), nil
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".local", "share", "live_chat"), nil
+	}
+}
+
+// Install copie l'exécutable courant (et le dossier mpv/ embarqué s'il
+// existe) vers destDir, puis enregistre le résultat auprès du mécanisme de
+// démarrage automatique de l'OS. La copie est sautée si l'exécutable existe
+// déjà dans destDir; l'enregistrement au démarrage automatique est refait à
+// chaque appel.
+func Install(destDir string) (execPath string, err error) {
+	exPath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("récupération du chemin de l'exécutable: %w", err)
+	}
+
+	destPath := filepath.Join(destDir, filepath.Base(exPath))
+	mpvSourceDir := filepath.Join(filepath.Dir(exPath), "mpv")
+	mpvDestDir := filepath.Join(destDir, "mpv")
+
+	if _, err := os.Stat(destPath); os.IsNotExist(err) {
+		if err := os.MkdirAll(destDir, 0755); err != nil {
+			return "", fmt.Errorf("création du répertoire de destination: %w", err)
+		}
+
+		if err := copyFile(exPath, destPath); err != nil {
+			return "", fmt.Errorf("copie de l'exécutable: %w", err)
+		}
+
+		if _, err := os.Stat(mpvSourceDir); !os.IsNotExist(err) {
+			log.Println("Copie du dossier mpv et de ses dépendances...")
+			if err := copyDir(mpvSourceDir, mpvDestDir); err != nil {
+				return "", fmt.Errorf("copie du dossier mpv: %w", err)
+			}
+			log.Println("Copie du dossier mpv terminée.")
+		} else {
+			log.Println("Le dossier mpv n'a pas été trouvé.")
+		}
+	}
+
+	if err := NewAutostart().Enable(destPath); err != nil {
+		return destPath, fmt.Errorf("configuration du démarrage automatique: %w", err)
+	}
+	return destPath, nil
+}
+
 // copyDir copie de manière récursive un répertoire source vers un répertoire de destination
 func copyDir(src, dst string) error {
 	srcInfo, err := os.Stat(src)
@@ -52,72 +148,3 @@ func copyFile(src, dst string) error {
 	_, err = io.Copy(dstFile, srcFile)
 	return err
 }
-
-func SetupStartup() {
-	exPath, err := os.Executable()
-	if err != nil {
-		log.Println("Erreur lors de la récupération du chemin de l'exécutable:", err)
-		return
-	}
-
-	programFilesPath := os.Getenv("ProgramFiles")
-	if programFilesPath == "" {
-		log.Println("Variable d'environnement non trouvée.")
-		return
-	}
-
-	destDir := filepath.Join(programFilesPath, "live_chat")
-	destPath := filepath.Join(destDir, filepath.Base(exPath))
-	mpvSourceDir := filepath.Join(filepath.Dir(exPath), "mpv")
-	mpvDestDir := filepath.Join(destDir, "mpv")
-
-	if _, err := os.Stat(destPath); !os.IsNotExist(err) {
-		log.Println("good")
-	} else {
-		if err := os.MkdirAll(destDir, 0755); err != nil {
-			log.Println("Impossible de créer le répertoire de destination:", err)
-			return
-		}
-
-		if err := copyFile(exPath, destPath); err != nil {
-			log.Println("Erreur lors de la copie de l'exécutable:", err)
-			return
-		}
-
-		if _, err := os.Stat(mpvSourceDir); !os.IsNotExist(err) {
-			log.Println("Copie du dossier mpv et de ses dépendances...")
-			if err := copyDir(mpvSourceDir, mpvDestDir); err != nil {
-				log.Println("Erreur lors de la copie du dossier mpv:", err)
-				return
-			}
-			log.Println("Copie du dossier mpv terminée.")
-		} else {
-			log.Println("Le dossier mpv n'a pas été trouvé.")
-		}
-	}
-
-	startupPath := filepath.Join(os.Getenv("APPDATA"), "Microsoft", "Windows", "Start Menu", "Programs", "Startup")
-	vbsContent := `Set WshShell = WScript.CreateObject("WScript.Shell")` + "\n" +
-		`WshShell.Run Chr(34) & "` + destPath + `" & Chr(34), 0` + "\n" +
-		`Set WshShell = Nothing`
-
-	vbsPath := filepath.Join(startupPath, "start_live_chat.vbs")
-	err = os.WriteFile(vbsPath, []byte(vbsContent), 0644)
-	if err != nil {
-		log.Println("Impossible de créer le script de démarrage:", err)
-	}
-}
-
-func SetupFirewall(port string) {
-	log.Println("Configuration du pare-feu Windows pour autoriser le port +" + port + "+...")
-	cmd := exec.Command("netsh", "advfirewall", "firewall", "add", "rule",
-		"name=live_chat", "dir=in", "action=allow", "protocol=TCP", "localport="+port)
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		log.Printf("Erreur lors de la configuration du pare-feu: %s\n", err)
-		log.Printf("Sortie de la commande: %s\n", output)
-		return
-	}
-	log.Println("Règle de pare-feu pour le port 3030 ajoutée avec succès.")
-}
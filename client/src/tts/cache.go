@@ -0,0 +1,55 @@
+package tts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+)
+
+var cacheExtensions = []string{"mp3", "wav"}
+
+// cacheKey identifie un clip par fournisseur, voix, langue et texte, pour
+// que deux demandes identiques réutilisent le même fichier en cache.
+func cacheKey(provider, voice, lang, text string) string {
+	sum := sha256.Sum256([]byte(provider + "|" + voice + "|" + lang + "|" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// cacheDir retourne (en la créant si besoin) le répertoire où sont stockés
+// les clips synthétisés.
+func cacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	dir = filepath.Join(dir, "live_chat", "tts")
+	_ = os.MkdirAll(dir, 0755)
+	return dir
+}
+
+// lookupCache cherche un clip déjà synthétisé par l'un des providers donnés,
+// dans leur ordre de priorité.
+func lookupCache(providers []Synthesizer, voice, lang, text string) (path string, format string, ok bool) {
+	for _, s := range providers {
+		key := cacheKey(s.Name(), voice, lang, text)
+		for _, ext := range cacheExtensions {
+			p := filepath.Join(cacheDir(), key+"."+ext)
+			if _, err := os.Stat(p); err == nil {
+				return p, ext, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// store écrit un clip fraîchement synthétisé dans le cache et retourne son
+// chemin.
+func store(provider, voice, lang, text, format string, audio []byte) (string, error) {
+	key := cacheKey(provider, voice, lang, text)
+	path := filepath.Join(cacheDir(), key+"."+format)
+	if err := os.WriteFile(path, audio, 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,49 @@
+package tts
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// googleConfig décrit le fichier de configuration du fournisseur Google
+// Cloud TTS, déposé par l'utilisateur dans le répertoire de config de l'OS
+// (cf. googleConfigPath): {"api_key": "..."}.
+type googleConfig struct {
+	APIKey string `json:"api_key"`
+}
+
+// googleConfigPath retourne l'emplacement attendu de la config Google Cloud
+// TTS: %ProgramData%/live_chat/tts_google.json sous Windows, équivalent XDG
+// ailleurs.
+func googleConfigPath() string {
+	if runtime.GOOS == "windows" {
+		base := os.Getenv("ProgramData")
+		if base == "" {
+			base = `C:\ProgramData`
+		}
+		return filepath.Join(base, "live_chat", "tts_google.json")
+	}
+
+	base, err := os.UserConfigDir()
+	if err != nil {
+		base = os.Getenv("HOME")
+	}
+	return filepath.Join(base, "live_chat", "tts_google.json")
+}
+
+// loadGoogleAPIKey lit la clé API Google Cloud TTS depuis le fichier de
+// config, ou retourne "" si elle n'est pas configurée.
+func loadGoogleAPIKey() string {
+	data, err := os.ReadFile(googleConfigPath())
+	if err != nil {
+		return ""
+	}
+
+	var cfg googleConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return ""
+	}
+	return cfg.APIKey
+}
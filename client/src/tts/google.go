@@ -0,0 +1,84 @@
+package tts
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// synthesizeTimeout borne l'appel réseau au fournisseur, pour éviter qu'une
+// synthèse bloquée ne coince indéfiniment la file d'attente (Speak est
+// appelé depuis la goroutine de lecture séquentielle).
+const synthesizeTimeout = 15 * time.Second
+
+// googleSynthesizer appelle l'API Google Cloud Text-to-Speech.
+type googleSynthesizer struct {
+	apiKey string
+}
+
+func (g *googleSynthesizer) Name() string { return "google" }
+
+type googleSynthesizeRequest struct {
+	Input struct {
+		Text string `json:"text"`
+	} `json:"input"`
+	Voice struct {
+		LanguageCode string `json:"languageCode"`
+		Name         string `json:"name,omitempty"`
+	} `json:"voice"`
+	AudioConfig struct {
+		AudioEncoding string `json:"audioEncoding"`
+	} `json:"audioConfig"`
+}
+
+type googleSynthesizeResponse struct {
+	AudioContent string `json:"audioContent"`
+}
+
+func (g *googleSynthesizer) Synthesize(text, lang, voice string) ([]byte, string, error) {
+	if g.apiKey == "" {
+		return nil, "", fmt.Errorf("clé API Google Cloud TTS manquante")
+	}
+	if lang == "" {
+		lang = "en-US"
+	}
+
+	var reqBody googleSynthesizeRequest
+	reqBody.Input.Text = text
+	reqBody.Voice.LanguageCode = lang
+	reqBody.Voice.Name = voice
+	reqBody.AudioConfig.AudioEncoding = "MP3"
+
+	payload, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, "", err
+	}
+
+	endpoint := "https://texttospeech.googleapis.com/v1/text:synthesize?key=" + g.apiKey
+	client := &http.Client{Timeout: synthesizeTimeout}
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, "", fmt.Errorf("Google Cloud TTS: %s: %s", resp.Status, body)
+	}
+
+	var out googleSynthesizeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, "", err
+	}
+
+	audio, err := base64.StdEncoding.DecodeString(out.AudioContent)
+	if err != nil {
+		return nil, "", err
+	}
+	return audio, "mp3", nil
+}
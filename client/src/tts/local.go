@@ -0,0 +1,80 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// localSynthesizer shell out vers le moteur TTS du système quand Google
+// Cloud TTS n'est pas configuré ou échoue: espeak-ng sur Linux, say sur
+// macOS, SAPI (via PowerShell) sur Windows.
+type localSynthesizer struct{}
+
+func (l *localSynthesizer) Name() string { return "local" }
+
+func (l *localSynthesizer) Synthesize(text, lang, voice string) ([]byte, string, error) {
+	tmp, err := os.CreateTemp("", "live_chat_tts_*.wav")
+	if err != nil {
+		return nil, "", err
+	}
+	outPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(outPath)
+
+	// Le moteur TTS local tourne sur la goroutine de lecture séquentielle
+	// de la file d'attente: un process qui ne rend jamais la main (binaire
+	// manquant qui attend sur stdin, voix bloquée, ...) la figerait sinon
+	// indéfiniment, comme pour le délai déjà posé sur le chemin Google.
+	ctx, cancel := context.WithTimeout(context.Background(), synthesizeTimeout)
+	defer cancel()
+
+	cmd := localCommand(ctx, text, lang, voice, outPath)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, "", fmt.Errorf("%s: %w: %s", cmd.Path, err, out)
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, "wav", nil
+}
+
+func localCommand(ctx context.Context, text, lang, voice, outPath string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "windows":
+		return sapiCommand(ctx, text, outPath)
+	case "darwin":
+		args := []string{"-o", outPath, "--data-format=LEF32@22050"}
+		if voice != "" {
+			args = append(args, "-v", voice)
+		}
+		args = append(args, text)
+		return exec.CommandContext(ctx, "say", args...)
+	default:
+		args := []string{"-w", outPath}
+		switch {
+		case voice != "":
+			args = append(args, "-v", voice)
+		case lang != "":
+			args = append(args, "-v", lang)
+		}
+		args = append(args, text)
+		return exec.CommandContext(ctx, "espeak-ng", args...)
+	}
+}
+
+// sapiCommand pilote System.Speech.Synthesis.SpeechSynthesizer via
+// PowerShell, pour ne pas avoir à lier cgo/SAPI directement.
+func sapiCommand(ctx context.Context, text, outPath string) *exec.Cmd {
+	script := fmt.Sprintf(
+		`Add-Type -AssemblyName System.Speech; `+
+			`$s = New-Object System.Speech.Synthesis.SpeechSynthesizer; `+
+			`$s.SetOutputToWaveFile(%q); $s.Speak(%q); $s.Dispose()`,
+		outPath, text,
+	)
+	return exec.CommandContext(ctx, "powershell", "-NoProfile", "-Command", script)
+}
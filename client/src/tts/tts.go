@@ -0,0 +1,59 @@
+// Package tts synthétise du texte en parole via un fournisseur
+// configurable (Google Cloud TTS par défaut, repli sur un moteur local) et
+// met les clips en cache sur disque pour éviter de resynthétiser les
+// phrases déjà demandées.
+package tts
+
+import (
+	"fmt"
+	"log"
+)
+
+// Synthesizer transforme du texte en audio pour la langue/voix demandées.
+// Le format retourné ("mp3" ou "wav") indique comment le clip doit être
+// décodé par les lecteurs qui ne savent pas ouvrir n'importe quel format.
+type Synthesizer interface {
+	Name() string
+	Synthesize(text, lang, voice string) (audio []byte, format string, err error)
+}
+
+// Speak synthétise text dans la langue lang avec la voix voice (optionnelle)
+// et retourne le chemin du clip mis en cache sur disque ainsi que son
+// format. Google Cloud TTS est utilisé en priorité quand une clé API est
+// configurée ; le moteur local (espeak-ng/say/SAPI) sert de repli.
+func Speak(text, lang, voice string) (path string, format string, err error) {
+	if path, format, ok := lookupCache(providers(), voice, lang, text); ok {
+		return path, format, nil
+	}
+
+	var lastErr error
+	for _, s := range providers() {
+		audio, format, err := s.Synthesize(text, lang, voice)
+		if err != nil {
+			lastErr = err
+			log.Printf("tts: le moteur %s a échoué: %v", s.Name(), err)
+			continue
+		}
+		path, err := store(s.Name(), voice, lang, text, format, audio)
+		if err != nil {
+			return "", "", err
+		}
+		return path, format, nil
+	}
+
+	if lastErr != nil {
+		return "", "", fmt.Errorf("aucun moteur de synthèse vocale disponible: %w", lastErr)
+	}
+	return "", "", fmt.Errorf("aucun moteur de synthèse vocale disponible")
+}
+
+// providers liste les moteurs de synthèse dans l'ordre de priorité: Google
+// Cloud TTS s'il est configuré, puis le moteur local en repli.
+func providers() []Synthesizer {
+	list := make([]Synthesizer, 0, 2)
+	if key := loadGoogleAPIKey(); key != "" {
+		list = append(list, &googleSynthesizer{apiKey: key})
+	}
+	list = append(list, &localSynthesizer{})
+	return list
+}
@@ -0,0 +1,19 @@
+package websocket
+
+import "fmt"
+
+var defaultClient *Client
+
+// SetDefault enregistre c comme client par défaut utilisé par SendJSON.
+func SetDefault(c *Client) {
+	defaultClient = c
+}
+
+// SendJSON envoie v sur le client par défaut, pour les sous-systèmes qui
+// n'ont pas de référence directe au Client (file d'attente, TTS, ...).
+func SendJSON(v interface{}) error {
+	if defaultClient == nil {
+		return fmt.Errorf("websocket: client par défaut non initialisé")
+	}
+	return defaultClient.SendJSON(v)
+}
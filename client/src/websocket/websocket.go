@@ -1,47 +1,188 @@
+// Package websocket maintient la connexion au serveur live_chat: backoff
+// exponentiel avec jitter complet sur les reconnexions, ping/pong pour
+// détecter une connexion morte, et un SendJSON pour que d'autres sous-
+// systèmes (file d'attente, TTS) puissent répondre au serveur.
 package websocket
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"live_chat/src/config"
 	"live_chat/src/event"
 	"log"
-	"net/url"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
-var ClientConn *websocket.Conn
+const (
+	pingInterval = 20 * time.Second
+	readTimeout  = 2 * pingInterval
+)
 
-func ConnectToWebsocket(wsURL string) {
-	u, err := url.Parse(wsURL)
-	if err != nil {
-		log.Fatal("URL WebSocket invalide:", err)
+// Client gère une connexion websocket reconnectante vers le serveur
+// live_chat.
+type Client struct {
+	urlFunc func() string
+
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+// NewClient crée un Client qui se connecte à l'URL retournée par urlFunc à
+// chaque tentative, ce qui permet de suivre un changement de serveur dans
+// la config rechargée à chaud sans redémarrer le client.
+func NewClient(urlFunc func() string) *Client {
+	return &Client{urlFunc: urlFunc}
+}
+
+// Run dial et sert la connexion jusqu'à ce que ctx soit annulé, en
+// reconnectant avec un backoff exponentiel tronqué et un jitter complet
+// (algorithme AWS: sleep = random(0, min(cap, base*2^tentative))). Le
+// compteur de tentatives est remis à zéro dès qu'un message est reçu.
+func (c *Client) Run(ctx context.Context) {
+	attempt := 0
+	for ctx.Err() == nil {
+		hadMessage, err := c.connectAndServe(ctx)
+		if err != nil {
+			log.Println("websocket:", err)
+		}
+
+		if hadMessage {
+			attempt = 0
+		} else {
+			attempt++
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		backoffCfg := config.Current().ReconnectBackoff
+		d := backoffDuration(attempt,
+			time.Duration(backoffCfg.BaseMillis)*time.Millisecond,
+			time.Duration(backoffCfg.CapSeconds)*time.Second,
+		)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d):
+		}
+	}
+}
+
+// backoffDuration implémente le backoff tronqué à jitter complet décrit
+// par l'algorithme AWS: sleep = random(0, min(cap, base*2^tentative)).
+func backoffDuration(attempt int, base, capDuration time.Duration) time.Duration {
+	if attempt < 0 {
+		attempt = 0
+	}
+	shift := attempt
+	if shift > 20 {
+		shift = 20
+	}
+
+	scaled := base << uint(shift)
+	if scaled <= 0 || scaled > capDuration {
+		scaled = capDuration
+	}
+	if scaled <= 0 {
+		return 0
 	}
+	return time.Duration(rand.Int63n(int64(scaled) + 1))
+}
 
-	c, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+// connectAndServe ouvre une connexion, la sert jusqu'à erreur/fermeture, et
+// retourne si au moins un message a été reçu du serveur (pour la remise à
+// zéro du backoff).
+func (c *Client) connectAndServe(ctx context.Context) (hadMessage bool, err error) {
+	url := c.urlFunc()
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
 	if err != nil {
-		log.Println("Échec de la connexion au serveur WebSocket:", err)
-		time.Sleep(time.Second)
-		return
+		return false, fmt.Errorf("connexion à %s: %w", url, err)
 	}
 
-	ClientConn = c
-	log.Printf("Connecté au serveur WebSocket: %s", u.String())
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.conn = nil
+		c.mu.Unlock()
+		conn.Close()
+	}()
+
+	log.Printf("Connecté au serveur WebSocket: %s", url)
+
+	conn.SetReadDeadline(time.Now().Add(readTimeout))
+	conn.SetPongHandler(func(string) error {
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
+		return nil
+	})
+
+	serveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go c.pingLoop(serveCtx, conn)
 
-	// Écoute des messages du serveur en arrière-plan
-	defer ClientConn.Close()
 	for {
-		typeM, message, err := ClientConn.ReadMessage()
+		typeM, message, err := conn.ReadMessage()
 		if err != nil {
-			log.Println("Erreur de lecture WebSocket:", err)
-			return
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				log.Println("websocket: fermeture normale par le serveur")
+				return hadMessage, nil
+			}
+			return hadMessage, fmt.Errorf("lecture: %w", err)
 		}
+		hadMessage = true
+
 		log.Printf("Message reçu du serveur distant: %s, %d", message, typeM)
 		var messageJSON map[string]string
-		err = json.Unmarshal(message, &messageJSON)
-		if err != nil {
+		if err := json.Unmarshal(message, &messageJSON); err != nil {
 			log.Println("error:", err)
+			continue
 		}
 		go event.DispatchEvent(messageJSON)
 	}
 }
+
+// pingLoop envoie un ping toutes les pingInterval tant que serveCtx est
+// actif, pour détecter une connexion TCP morte avant readTimeout.
+func (c *Client) pingLoop(ctx context.Context, conn *websocket.Conn) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			err := conn.WriteMessage(websocket.PingMessage, nil)
+			c.mu.Unlock()
+			if err != nil {
+				log.Println("websocket: ping:", err)
+				return
+			}
+		}
+	}
+}
+
+// SendJSON encode v et l'envoie sur la connexion active, sous mutex, pour
+// que d'autres sous-systèmes (file d'attente, TTS) puissent répondre au
+// serveur sur la même connexion.
+func (c *Client) SendJSON(v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return fmt.Errorf("websocket: non connecté")
+	}
+	return c.conn.WriteMessage(websocket.TextMessage, payload)
+}